@@ -0,0 +1,170 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// cacheEntry holds a cached weather value along with when it was fetched
+type cacheEntry struct {
+	weather   *Weather
+	fetchedAt time.Time
+}
+
+// CacheMetrics holds counters exposed via the /metrics endpoint
+type CacheMetrics struct {
+	Hits     int64 `json:"hits"`
+	Misses   int64 `json:"misses"`
+	Stale    int64 `json:"stale"`
+	Inflight int64 `json:"inflight"`
+}
+
+// Cache is the interface WeatherService uses to look up and store weather data
+type Cache interface {
+	// Get returns the cached weather for key, whether it's still fresh, and whether it was found at all
+	Get(key string) (weather *Weather, fresh bool, found bool)
+	Set(key string, weather *Weather)
+	Metrics() CacheMetrics
+}
+
+const cacheShardCount = 32
+
+// shardedCache is an in-memory Cache implementation split into shards to reduce lock contention
+type shardedCache struct {
+	shards    [cacheShardCount]*cacheShard
+	freshTTL  time.Duration
+	staleTTL  time.Duration
+	hits      int64
+	misses    int64
+	staleHits int64
+}
+
+type cacheShard struct {
+	mu      sync.RWMutex
+	entries map[string]cacheEntry
+}
+
+// NewShardedCache creates an in-memory Cache with the given fresh and stale TTLs
+func NewShardedCache(freshTTL, staleTTL time.Duration) *shardedCache {
+	c := &shardedCache{freshTTL: freshTTL, staleTTL: staleTTL}
+	for i := range c.shards {
+		c.shards[i] = &cacheShard{entries: make(map[string]cacheEntry)}
+	}
+	return c
+}
+
+func (c *shardedCache) shardFor(key string) *cacheShard {
+	var h uint32
+	for i := 0; i < len(key); i++ {
+		h = h*31 + uint32(key[i])
+	}
+	return c.shards[h%cacheShardCount]
+}
+
+func (c *shardedCache) Get(key string) (*Weather, bool, bool) {
+	shard := c.shardFor(key)
+	shard.mu.RLock()
+	entry, ok := shard.entries[key]
+	shard.mu.RUnlock()
+
+	if !ok {
+		atomic.AddInt64(&c.misses, 1)
+		return nil, false, false
+	}
+
+	age := time.Since(entry.fetchedAt)
+	if age > c.staleTTL {
+		atomic.AddInt64(&c.misses, 1)
+		c.evict(shard, key, entry.fetchedAt)
+		return nil, false, false
+	}
+	if age > c.freshTTL {
+		atomic.AddInt64(&c.staleHits, 1)
+		return entry.weather, false, true
+	}
+
+	atomic.AddInt64(&c.hits, 1)
+	return entry.weather, true, true
+}
+
+// evict removes key from shard if it's still the entry we just found stale, guarding
+// against a concurrent Set having already replaced it with a fresh one.
+func (c *shardedCache) evict(shard *cacheShard, key string, fetchedAt time.Time) {
+	shard.mu.Lock()
+	if entry, ok := shard.entries[key]; ok && entry.fetchedAt.Equal(fetchedAt) {
+		delete(shard.entries, key)
+	}
+	shard.mu.Unlock()
+}
+
+func (c *shardedCache) Set(key string, weather *Weather) {
+	shard := c.shardFor(key)
+	shard.mu.Lock()
+	shard.entries[key] = cacheEntry{weather: weather, fetchedAt: time.Now()}
+	shard.mu.Unlock()
+}
+
+// Metrics returns the cache's own hit/miss/stale counters. Inflight is left zero here;
+// WeatherService.Metrics fills it in from the singleflight group, which is the only place
+// that tracks in-flight upstream fetches.
+func (c *shardedCache) Metrics() CacheMetrics {
+	return CacheMetrics{
+		Hits:   atomic.LoadInt64(&c.hits),
+		Misses: atomic.LoadInt64(&c.misses),
+		Stale:  atomic.LoadInt64(&c.staleHits),
+	}
+}
+
+// normalizeCityKey normalizes a city name into a stable cache key
+func normalizeCityKey(city string) string {
+	return strings.ToLower(strings.TrimSpace(city))
+}
+
+// singleflightGroup coalesces concurrent calls for the same key into a single execution of fn
+type singleflightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*singleflightCall
+}
+
+type singleflightCall struct {
+	wg     sync.WaitGroup
+	result *Weather
+	err    error
+}
+
+func newSingleflightGroup() *singleflightGroup {
+	return &singleflightGroup{calls: make(map[string]*singleflightCall)}
+}
+
+// Len reports the number of calls currently in flight
+func (g *singleflightGroup) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.calls)
+}
+
+// Do executes fn for key, or waits for an in-flight execution to finish if one is already running
+func (g *singleflightGroup) Do(key string, fn func() (*Weather, error)) (*Weather, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.result, call.err
+	}
+
+	call := &singleflightCall{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.result, call.err = fn()
+	call.wg.Done()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+
+	return call.result, call.err
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Config holds the runtime configuration that's required up front to construct the App
+// and HTTP server. Tunables that only matter deeper in the stack (cache TTLs, breaker
+// thresholds, rate limits) are read directly from the environment where they're used,
+// the same way cacheFreshTTL and breakerCooldown are in main.go.
+type Config struct {
+	APIKey      string
+	Port        string
+	DefaultCity string
+	HTTPTimeout time.Duration
+}
+
+// LoadConfig reads configuration from the environment, failing fast if a required
+// value is missing.
+func LoadConfig() (*Config, error) {
+	apiKey := os.Getenv("WEATHER_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("WEATHER_API_KEY is required")
+	}
+
+	return &Config{
+		APIKey:      apiKey,
+		Port:        envOr("PORT", "8080"),
+		DefaultCity: envOr("DEFAULT_CITY", "Algiers"),
+		HTTPTimeout: envDuration("HTTP_TIMEOUT", 10*time.Second),
+	}, nil
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestLoadConfig table-drives Config's env-var parsing and its fail-fast validation
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+		check   func(t *testing.T, cfg *Config)
+	}{
+		{
+			name:    "missing API key fails",
+			env:     map[string]string{},
+			wantErr: true,
+		},
+		{
+			name: "defaults are applied",
+			env:  map[string]string{"WEATHER_API_KEY": "test-key"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Port != "8080" {
+					t.Errorf("expected default port 8080, got %s", cfg.Port)
+				}
+				if cfg.DefaultCity != "Algiers" {
+					t.Errorf("expected default city Algiers, got %s", cfg.DefaultCity)
+				}
+				if cfg.HTTPTimeout != 10*time.Second {
+					t.Errorf("expected default HTTP timeout 10s, got %s", cfg.HTTPTimeout)
+				}
+			},
+		},
+		{
+			name: "env vars override defaults",
+			env: map[string]string{
+				"WEATHER_API_KEY": "test-key",
+				"PORT":            "9090",
+				"DEFAULT_CITY":    "Oran",
+				"HTTP_TIMEOUT":    "5s",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Port != "9090" {
+					t.Errorf("expected port 9090, got %s", cfg.Port)
+				}
+				if cfg.DefaultCity != "Oran" {
+					t.Errorf("expected city Oran, got %s", cfg.DefaultCity)
+				}
+				if cfg.HTTPTimeout != 5*time.Second {
+					t.Errorf("expected HTTP timeout 5s, got %s", cfg.HTTPTimeout)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"WEATHER_API_KEY", "PORT", "DEFAULT_CITY", "HTTP_TIMEOUT"} {
+				t.Setenv(key, "")
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			cfg, err := LoadConfig()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
@@ -1,17 +1,35 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 )
 
 // App struct holds the dependencies for the application
 type App struct {
 	weatherService *WeatherService
+	defaultCity    string
+}
+
+// HourForecast is a single hour of forecast data, shared by Weather, weatherCardView
+// and their tests so the record is defined in exactly one place.
+type HourForecast struct {
+	TimeEpoch int64   `json:"time_epoch"`
+	TempC     float64 `json:"temp_c"`
+	Condition struct {
+		Text string `json:"text"`
+		Icon string `json:"icon"`
+	} `json:"condition"`
+	ChanceOfRain float64 `json:"chance_of_rain"`
 }
 
 // Weather represents the JSON structure from the API
@@ -29,68 +47,196 @@ type Weather struct {
 	} `json:"current"`
 	Forecast struct {
 		Forecastday []struct {
-			Hour []struct {
-				TimeEpoch    int64   `json:"time_epoch"`
-				TempC        float64 `json:"temp_c"`
-				Condition    struct {
-					Text string `json:"text"`
-					Icon string `json:"icon"`
-				} `json:"condition"`
-				ChanceOfRain float64 `json:"chance_of_rain"`
-			} `json:"hour"`
+			Hour []HourForecast `json:"hour"`
 		} `json:"forecastday"`
 	} `json:"forecast"`
 }
 
-// WeatherService struct holds the API key and HTTP client
+// WeatherService fetches weather data through a Provider, with a caching layer in front
 type WeatherService struct {
 	APIKey string
 	Client *http.Client
+
+	provider Provider
+	cache    Cache
+	inflight *singleflightGroup
 }
 
-// NewWeatherService creates a new WeatherService instance
+// NewWeatherService creates a new WeatherService backed by a single WeatherAPIProvider
 func NewWeatherService(apiKey string, client *http.Client) *WeatherService {
+	return NewWeatherServiceWithProviders(apiKey, client, []Provider{NewWeatherAPIProvider(apiKey, client)})
+}
+
+// NewWeatherServiceWithProviders creates a WeatherService that fails over across providers,
+// trying them in order behind a circuit breaker per provider.
+func NewWeatherServiceWithProviders(apiKey string, client *http.Client, providers []Provider) *WeatherService {
 	return &WeatherService{
-		APIKey: apiKey,
-		Client: client,
+		APIKey:   apiKey,
+		Client:   client,
+		provider: NewMultiProvider(providers, breakerFailureThreshold(), breakerCooldown()),
+		cache:    NewShardedCache(cacheFreshTTL(), cacheStaleTTL()),
+		inflight: newSingleflightGroup(),
 	}
 }
 
-// FetchWeatherData fetches weather data from the API
-func (ws *WeatherService) FetchWeatherData(city string) (*Weather, error) {
-	apiUrl := fmt.Sprintf("https://api.weatherapi.com/v1/forecast.json?key=%s&q=%s&days=1", ws.APIKey, city)
+// breakerFailureThreshold reads BREAKER_FAILURE_THRESHOLD from the environment, defaulting to 3
+func breakerFailureThreshold() int {
+	return envInt("BREAKER_FAILURE_THRESHOLD", 3)
+}
 
-	resp, err := ws.Client.Get(apiUrl)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch data: %w", err)
+// breakerCooldown reads BREAKER_COOLDOWN from the environment, defaulting to 30 seconds
+func breakerCooldown() time.Duration {
+	return envDuration("BREAKER_COOLDOWN", 30*time.Second)
+}
+
+func envInt(key string, fallback int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
 	}
-	defer resp.Body.Close()
+	return fallback
+}
+
+// cacheFreshTTL reads CACHE_FRESH_TTL from the environment, falling back to the more
+// general CACHE_TTL and then to 10 minutes.
+func cacheFreshTTL() time.Duration {
+	return envDuration("CACHE_FRESH_TTL", envDuration("CACHE_TTL", 10*time.Minute))
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d for city %s", resp.StatusCode, city)
+// cacheStaleTTL reads CACHE_STALE_TTL from the environment, defaulting to 60 minutes
+func cacheStaleTTL() time.Duration {
+	return envDuration("CACHE_STALE_TTL", 60*time.Minute)
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
 	}
+	return fallback
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+// minForecastDays and maxForecastDays bound the days parameter accepted by FetchWeatherData,
+// matching the range WeatherAPI's forecast endpoint supports.
+const (
+	minForecastDays = 1
+	maxForecastDays = 10
+)
+
+// FetchWeatherData fetches weather data for city including days of forecast, serving from
+// cache when possible. Fresh entries are returned immediately. Stale entries are returned
+// immediately too, while a refresh is kicked off in the background. A hard miss blocks until
+// the upstream fetch completes, coalescing concurrent requests for the same city and days.
+func (ws *WeatherService) FetchWeatherData(city string, days int) (*Weather, error) {
+	if days < minForecastDays || days > maxForecastDays {
+		return nil, fmt.Errorf("days must be between %d and %d, got %d", minForecastDays, maxForecastDays, days)
+	}
+
+	key := cacheKey(city, days)
+
+	if weather, fresh, found := ws.cache.Get(key); found {
+		if !fresh {
+			go ws.refresh(city, days, key)
+		}
+		return weather, nil
+	}
+
+	return ws.fetchAndCache(city, days, key)
+}
+
+// cacheKey builds the cache key for a city/days pair
+func cacheKey(city string, days int) string {
+	return fmt.Sprintf("%s:%d", normalizeCityKey(city), days)
+}
+
+// refresh re-fetches city in the background and updates the cache, coalescing with
+// any other refresh already in flight for the same key.
+func (ws *WeatherService) refresh(city string, days int, key string) {
+	if _, err := ws.fetchAndCache(city, days, key); err != nil {
+		log.Printf("background refresh failed for %s: %v", city, err)
 	}
+}
 
-	var weather Weather
-	if err := json.Unmarshal(body, &weather); err != nil {
-		return nil, fmt.Errorf("failed to parse weather data: %w", err)
+// fetchAndCache fetches city from the upstream provider and stores the result in the
+// cache, coalescing concurrent callers for the same key into a single upstream request.
+func (ws *WeatherService) fetchAndCache(city string, days int, key string) (*Weather, error) {
+	weather, err := ws.inflight.Do(key, func() (*Weather, error) {
+		return ws.provider.FetchWeather(context.Background(), city, days)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &weather, nil
+	ws.cache.Set(key, weather)
+	return weather, nil
+}
+
+// Metrics returns the current cache metrics for this service, including the number
+// of upstream fetches currently in flight.
+func (ws *WeatherService) Metrics() CacheMetrics {
+	metrics := ws.cache.Metrics()
+	metrics.Inflight = int64(ws.inflight.Len())
+	return metrics
+}
+
+// Ping fetches city directly from the upstream provider, bypassing the cache, to
+// confirm the provider chain is reachable.
+func (ws *WeatherService) Ping(ctx context.Context, city string) error {
+	_, err := ws.provider.FetchWeather(ctx, city, minForecastDays)
+	return err
 }
 
 // FormatCurrentWeather formats the current weather data as a string
 func FormatCurrentWeather(weather *Weather) string {
 	location := fmt.Sprintf("%s, %s", weather.Location.Name, weather.Location.Country)
-	temp := fmt.Sprintf("%.1fÂ°C", weather.Current.TempC)
+	temp := fmt.Sprintf("%.1f°C", weather.Current.TempC)
 	condition := weather.Current.Condition.Text
 	return fmt.Sprintf("%s: %s, %s", location, temp, condition)
 }
 
+// FormatForecast formats the forecast days of a Weather as a string, one line per day,
+// summarizing each day's average temperature and a representative condition, mirroring
+// the level of detail FormatCurrentWeather gives for the current conditions.
+func FormatForecast(weather *Weather) string {
+	location := fmt.Sprintf("%s, %s", weather.Location.Name, weather.Location.Country)
+	lines := make([]string, 0, len(weather.Forecast.Forecastday))
+	for i, day := range weather.Forecast.Forecastday {
+		if len(day.Hour) == 0 {
+			lines = append(lines, fmt.Sprintf("Day %d: no hourly data available", i+1))
+			continue
+		}
+
+		var total float64
+		condition := day.Hour[0].Condition.Text
+		for _, hour := range day.Hour {
+			total += hour.TempC
+			if time.Unix(hour.TimeEpoch, 0).UTC().Hour() == 12 {
+				condition = hour.Condition.Text
+			}
+		}
+		avgTemp := total / float64(len(day.Hour))
+
+		lines = append(lines, fmt.Sprintf("Day %d: %.1f°C avg, %s", i+1, avgTemp, condition))
+	}
+	return fmt.Sprintf("%s\n%s", location, strings.Join(lines, "\n"))
+}
+
+// FormatHourly formats the first forecast day's hourly breakdown as a string
+func FormatHourly(weather *Weather) string {
+	location := fmt.Sprintf("%s, %s", weather.Location.Name, weather.Location.Country)
+	if len(weather.Forecast.Forecastday) == 0 {
+		return fmt.Sprintf("%s: no hourly data available", location)
+	}
+
+	lines := make([]string, 0, len(weather.Forecast.Forecastday[0].Hour))
+	for _, hour := range weather.Forecast.Forecastday[0].Hour {
+		lines = append(lines, fmt.Sprintf("%d: %.1f°C, %s, %.0f%% rain", hour.TimeEpoch, hour.TempC, hour.Condition.Text, hour.ChanceOfRain))
+	}
+	return fmt.Sprintf("%s\n%s", location, strings.Join(lines, "\n"))
+}
+
 // weatherHandler handles HTTP requests for weather data and is a method of App
 func (a *App) weatherHandler(w http.ResponseWriter, r *http.Request) {
 	// Get the city from the URL query parameters
@@ -100,7 +246,7 @@ func (a *App) weatherHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Use the pre-initialized weather service
-	weather, err := a.weatherService.FetchWeatherData(city)
+	weather, err := a.weatherService.FetchWeatherData(city, 1)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error fetching weather: %v", err), http.StatusInternalServerError)
 		return
@@ -113,26 +259,186 @@ func (a *App) weatherHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// forecastHandler handles HTTP requests for the multi-day forecast
+func (a *App) forecastHandler(w http.ResponseWriter, r *http.Request) {
+	city := r.URL.Query().Get("city")
+	if city == "" {
+		city = "Algiers"
+	}
+
+	days := minForecastDays
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid days parameter: %v", err), http.StatusBadRequest)
+			return
+		}
+		if parsed < minForecastDays || parsed > maxForecastDays {
+			http.Error(w, fmt.Sprintf("days must be between %d and %d, got %d", minForecastDays, maxForecastDays, parsed), http.StatusBadRequest)
+			return
+		}
+		days = parsed
+	}
+
+	weather, err := a.weatherService.FetchWeatherData(city, days)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error fetching weather: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(weather.Forecast)
+}
+
+// hourlyHandler handles HTTP requests for the current day's hourly breakdown
+func (a *App) hourlyHandler(w http.ResponseWriter, r *http.Request) {
+	city := r.URL.Query().Get("city")
+	if city == "" {
+		city = "Algiers"
+	}
+
+	weather, err := a.weatherService.FetchWeatherData(city, 1)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error fetching weather: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var hours any
+	if len(weather.Forecast.Forecastday) > 0 {
+		hours = weather.Forecast.Forecastday[0].Hour
+	} else {
+		hours = []struct{}{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"hourly": hours})
+}
+
+// indexHandler serves the HTML dashboard, pre-rendering the weather card for the
+// requested (or default) city so the page is useful without any JavaScript running first.
+func (a *App) indexHandler(w http.ResponseWriter, r *http.Request) {
+	city := r.URL.Query().Get("city")
+	if city == "" {
+		city = "Algiers"
+	}
+
+	renderTemplate(w, "index", weatherCardViewFor(a, city))
+}
+
+// weatherFragmentHandler handles the htmx form POST and re-renders just the weather card
+func (a *App) weatherFragmentHandler(w http.ResponseWriter, r *http.Request) {
+	city := r.FormValue("city")
+	if city == "" {
+		city = "Algiers"
+	}
+
+	renderTemplate(w, "weather-card", weatherCardViewFor(a, city))
+}
+
+// weatherCardViewFor fetches weather for city and builds the view data shared by the
+// index page and the weather card partial.
+func weatherCardViewFor(a *App, city string) weatherCardView {
+	weather, err := a.weatherService.FetchWeatherData(city, 1)
+	if err != nil {
+		return weatherCardView{City: city, Error: fmt.Sprintf("Error fetching weather: %v", err)}
+	}
+
+	view := weatherCardView{City: city, Weather: weather}
+	if len(weather.Forecast.Forecastday) > 0 {
+		view.Hours = weather.Forecast.Forecastday[0].Hour
+	}
+	return view
+}
+
+// metricsHandler exposes cache hit/miss/inflight counters as JSON
+func (a *App) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(a.weatherService.Metrics())
+}
+
+// healthzHandler is a liveness probe: if the process can answer HTTP at all, it's healthy
+func (a *App) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// readyzHandler is a readiness probe: it pings the upstream provider for the default
+// city and only reports ready if that succeeds.
+func (a *App) readyzHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	if err := a.weatherService.Ping(ctx, a.defaultCity); err != nil {
+		http.Error(w, fmt.Sprintf("not ready: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ready"))
+}
+
+// shutdownTimeout bounds how long main waits for in-flight requests to drain on shutdown
+const shutdownTimeout = 15 * time.Second
+
 // main function to start the server
 func main() {
-	// Get API key from environment variable
-	apiKey := "94474d04349f43008d395834240102"
-	if apiKey == "" {
-		log.Fatalf("API key not found. Please set the WEATHER_API_KEY environment variable.")
+	cfg, err := LoadConfig()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
 	}
 
-	// Create a new App instance with a real HTTP client
+	// Create a new App instance with a real HTTP client, wired up to fail over across
+	// whichever providers WEATHER_PROVIDERS selects (defaults to weatherapi only)
+	client := &http.Client{Timeout: cfg.HTTPTimeout}
 	app := &App{
-		weatherService: NewWeatherService(apiKey, &http.Client{Timeout: 10 * time.Second}),
+		weatherService: NewWeatherServiceWithProviders(cfg.APIKey, client, buildProvidersFromEnv(cfg.APIKey, client)),
+		defaultCity:    cfg.DefaultCity,
+	}
+
+	rl := newRateLimiter(rateLimitBurst(), rateLimitPerSecond())
+	srv := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: newRouter(app, rl),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		log.Printf("Starting server on port %s...", cfg.Port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	log.Println("Shutting down server...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Fatalf("Graceful shutdown failed: %v", err)
 	}
+	log.Println("Server stopped")
+}
 
-	// Register the handler for the /weather endpoint
-	http.HandleFunc("/weather", app.weatherHandler)
+// rateLimitBurst reads RATE_LIMIT_BURST from the environment, defaulting to 20 tokens
+func rateLimitBurst() float64 {
+	return envFloat("RATE_LIMIT_BURST", 20)
+}
+
+// rateLimitPerSecond reads RATE_LIMIT_PER_SECOND from the environment, defaulting to 5
+func rateLimitPerSecond() float64 {
+	return envFloat("RATE_LIMIT_PER_SECOND", 5)
+}
 
-	// Start the server on port 8080
-	port := "8080"
-	log.Printf("Starting server on port %s...", port)
-	if err := http.ListenAndServe(":"+port, nil); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+func envFloat(key string, fallback float64) float64 {
+	if v := os.Getenv(key); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
 	}
+	return fallback
 }
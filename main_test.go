@@ -1,250 +1,551 @@
-// main_test.go - Unit tests for the weather web server
-package main
-
-import (
-	"io"
-	"net/http"
-	"net/http/httptest"
-	"strings"
-	"testing"
-)
-
-// mockRoundTripper allows us to mock the HTTP client's behavior
-type mockRoundTripper struct {
-	Response     *http.Response
-	Error        error
-	RequestCheck func(*http.Request)
-}
-
-func (mrt *mockRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
-	if mrt.RequestCheck != nil {
-		mrt.RequestCheck(req)
-	}
-	return mrt.Response, mrt.Error
-}
-
-// TestNewWeatherService tests the creation of a new WeatherService
-func TestNewWeatherService(t *testing.T) {
-	apiKey := "test-key"
-	client := &http.Client{}
-	service := NewWeatherService(apiKey, client)
-
-	if service.APIKey != apiKey {
-		t.Errorf("Expected APIKey to be %s, got %s", apiKey, service.APIKey)
-	}
-	if service.Client != client {
-		t.Errorf("Expected Client to be the one provided")
-	}
-}
-
-// TestFetchWeatherData tests the API call with a mock client
-func TestFetchWeatherData(t *testing.T) {
-	// A sample successful API response body
-	successBody := `{
-		"location": {"name": "TestCity", "country": "TestCountry"},
-		"current": {"temp_c": 25.5, "condition": {"text": "Sunny"}},
-		"forecast": {"forecastday": [{"hour": []}]}
-	}`
-
-	// Test case for a successful API call
-	t.Run("Success", func(t *testing.T) {
-		mockClient := &http.Client{
-			Transport: &mockRoundTripper{
-				Response: &http.Response{
-					StatusCode: 200,
-					Body:       io.NopCloser(strings.NewReader(successBody)),
-				},
-			},
-		}
-
-		ws := NewWeatherService("test-key", mockClient)
-		weather, err := ws.FetchWeatherData("TestCity")
-		if err != nil {
-			t.Fatalf("Expected no error, got %v", err)
-		}
-		if weather.Location.Name != "TestCity" {
-			t.Errorf("Expected city to be TestCity, got %s", weather.Location.Name)
-		}
-	})
-
-	// Test case for a non-200 status code from the API
-	t.Run("API Error", func(t *testing.T) {
-		mockClient := &http.Client{
-			Transport: &mockRoundTripper{
-				Response: &http.Response{
-					StatusCode: 400,
-					Body:       io.NopCloser(strings.NewReader("Bad Request")),
-				},
-			},
-		}
-
-		ws := NewWeatherService("test-key", mockClient)
-		_, err := ws.FetchWeatherData("InvalidCity")
-		if err == nil {
-			t.Fatal("Expected an error, got none")
-		}
-		expectedErr := "API returned status 400 for city InvalidCity"
-		if err.Error() != expectedErr {
-			t.Errorf("Expected error %q, got %q", expectedErr, err.Error())
-		}
-	})
-
-	// Test case for invalid JSON response
-	t.Run("Invalid JSON", func(t *testing.T) {
-		mockClient := &http.Client{
-			Transport: &mockRoundTripper{
-				Response: &http.Response{
-					StatusCode: 200,
-					Body:       io.NopCloser(strings.NewReader("invalid json")),
-				},
-			},
-		}
-
-		ws := NewWeatherService("test-key", mockClient)
-		_, err := ws.FetchWeatherData("TestCity")
-		if err == nil {
-			t.Fatal("Expected an error, got none")
-		}
-		if !strings.Contains(err.Error(), "failed to parse weather data") {
-			t.Errorf("Expected parse error, got %v", err)
-		}
-	})
-}
-
-// TestFormatCurrentWeather tests the formatting of current weather data
-func TestFormatCurrentWeather(t *testing.T) {
-	weather := &Weather{
-		Location: struct {
-			Name    string `json:"name"`
-			Country string `json:"country"`
-		}{Name: "Casablanca", Country: "Morocco"},
-		Current: struct {
-			TempC     float64 `json:"temp_c"`
-			Condition struct {
-				Text string `json:"text"`
-				Icon string `json:"icon"`
-			} `json:"condition"`
-		}{TempC: 22.0, Condition: struct {
-			Text string `json:"text"`
-			Icon string `json:"icon"`
-		}{Text: "Partly cloudy"}},
-	}
-	expected := "Casablanca, Morocco: 22.0°C, Partly cloudy"
-	result := FormatCurrentWeather(weather)
-	if result != expected {
-		t.Errorf("Expected %q, got %q", expected, result)
-	}
-}
-
-// TestWeatherHandler tests the main HTTP handler
-func TestWeatherHandler(t *testing.T) {
-	t.Run("Successful request with city", func(t *testing.T) {
-		// Mock the API response for this specific test case
-		mockClient := &http.Client{
-			Transport: &mockRoundTripper{
-				Response: &http.Response{
-					StatusCode: 200,
-					Body:       io.NopCloser(strings.NewReader(`{"location": {"name": "TestCity", "country": "TestCountry"}, "current": {"temp_c": 25.0, "condition": {"text": "Sunny"}}}`)),
-				},
-			},
-		}
-
-		// Create a new App instance and inject the mock weather service
-		app := &App{weatherService: NewWeatherService("test-key", mockClient)}
-		req := httptest.NewRequest("GET", "/weather?city=TestCity", nil)
-		rr := httptest.NewRecorder()
-		handler := http.HandlerFunc(app.weatherHandler)
-		handler.ServeHTTP(rr, req)
-
-		if status := rr.Code; status != http.StatusOK {
-			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
-		}
-		expected := `{"weather":"TestCity, TestCountry: 25.0°C, Sunny"}` + "\n"
-		if rr.Body.String() != expected {
-			t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), expected)
-		}
-	})
-
-	t.Run("Request without city", func(t *testing.T) {
-		// Mock the API response for this specific test case
-		mockClient := &http.Client{
-			Transport: &mockRoundTripper{
-				Response: &http.Response{
-					StatusCode: 200,
-					Body:       io.NopCloser(strings.NewReader(`{"location": {"name": "Algiers", "country": "Algeria"}, "current": {"temp_c": 20.0, "condition": {"text": "Cloudy"}}}`)),
-				},
-			},
-		}
-
-		app := &App{weatherService: NewWeatherService("test-key", mockClient)}
-		req := httptest.NewRequest("GET", "/weather", nil)
-		rr := httptest.NewRecorder()
-		handler := http.HandlerFunc(app.weatherHandler)
-		handler.ServeHTTP(rr, req)
-
-		if status := rr.Code; status != http.StatusOK {
-			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
-		}
-		expected := `{"weather":"Algiers, Algeria: 20.0°C, Cloudy"}` + "\n"
-		if rr.Body.String() != expected {
-			t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), expected)
-		}
-	})
-
-	t.Run("API error response", func(t *testing.T) {
-		// Mock the API response to be a 401 error
-		mockClient := &http.Client{
-			Transport: &mockRoundTripper{
-				Response: &http.Response{
-					StatusCode: 401,
-					Body:       io.NopCloser(strings.NewReader("Unauthorized")),
-				},
-			},
-		}
-
-		app := &App{weatherService: NewWeatherService("test-key", mockClient)}
-		req := httptest.NewRequest("GET", "/weather?city=TestCity", nil)
-		rr := httptest.NewRecorder()
-		handler := http.HandlerFunc(app.weatherHandler)
-		handler.ServeHTTP(rr, req)
-
-		if status := rr.Code; status != http.StatusInternalServerError {
-			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusInternalServerError)
-		}
-		expected := "Error fetching weather: API returned status 401 for city TestCity\n"
-		if rr.Body.String() != expected {
-			t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), expected)
-		}
-	})
-
-	t.Run("API key not set", func(t *testing.T) {
-		// Mock the API response to be a 400 error because the key is missing
-		mockClient := &http.Client{
-			Transport: &mockRoundTripper{
-				Response: &http.Response{
-					StatusCode: 400,
-					Body:       io.NopCloser(strings.NewReader("Bad Request")),
-				},
-			},
-		}
-
-		app := &App{
-			weatherService: NewWeatherService("", mockClient),
-		}
-
-		req := httptest.NewRequest("GET", "/weather", nil)
-		rr := httptest.NewRecorder()
-		handler := http.HandlerFunc(app.weatherHandler)
-
-		handler.ServeHTTP(rr, req)
-
-		if status := rr.Code; status != http.StatusInternalServerError {
-			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusInternalServerError)
-		}
-
-		expected := "Error fetching weather: API returned status 400 for city Algiers\n"
-		if rr.Body.String() != expected {
-			t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), expected)
-		}
-	})
-}
+// main_test.go - Unit tests for the weather web server
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// mockRoundTripper allows us to mock the HTTP client's behavior
+type mockRoundTripper struct {
+	Response     *http.Response
+	Error        error
+	RequestCheck func(*http.Request)
+}
+
+func (mrt *mockRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if mrt.RequestCheck != nil {
+		mrt.RequestCheck(req)
+	}
+	return mrt.Response, mrt.Error
+}
+
+// TestNewWeatherService tests the creation of a new WeatherService
+func TestNewWeatherService(t *testing.T) {
+	apiKey := "test-key"
+	client := &http.Client{}
+	service := NewWeatherService(apiKey, client)
+
+	if service.APIKey != apiKey {
+		t.Errorf("Expected APIKey to be %s, got %s", apiKey, service.APIKey)
+	}
+	if service.Client != client {
+		t.Errorf("Expected Client to be the one provided")
+	}
+}
+
+// TestFetchWeatherData tests the API call with a mock client
+func TestFetchWeatherData(t *testing.T) {
+	// A sample successful API response body
+	successBody := `{
+		"location": {"name": "TestCity", "country": "TestCountry"},
+		"current": {"temp_c": 25.5, "condition": {"text": "Sunny"}},
+		"forecast": {"forecastday": [{"hour": []}]}
+	}`
+
+	// Test case for a successful API call
+	t.Run("Success", func(t *testing.T) {
+		mockClient := &http.Client{
+			Transport: &mockRoundTripper{
+				Response: &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(strings.NewReader(successBody)),
+				},
+			},
+		}
+
+		ws := NewWeatherService("test-key", mockClient)
+		weather, err := ws.FetchWeatherData("TestCity", 1)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if weather.Location.Name != "TestCity" {
+			t.Errorf("Expected city to be TestCity, got %s", weather.Location.Name)
+		}
+	})
+
+	// Test case for a non-200 status code from the API
+	t.Run("API Error", func(t *testing.T) {
+		mockClient := &http.Client{
+			Transport: &mockRoundTripper{
+				Response: &http.Response{
+					StatusCode: 400,
+					Body:       io.NopCloser(strings.NewReader("Bad Request")),
+				},
+			},
+		}
+
+		ws := NewWeatherService("test-key", mockClient)
+		_, err := ws.FetchWeatherData("InvalidCity", 1)
+		if err == nil {
+			t.Fatal("Expected an error, got none")
+		}
+		expectedErr := "provider weatherapi: API returned status 400 for city InvalidCity"
+		if err.Error() != expectedErr {
+			t.Errorf("Expected error %q, got %q", expectedErr, err.Error())
+		}
+	})
+
+	// Test case for invalid JSON response
+	t.Run("Invalid JSON", func(t *testing.T) {
+		mockClient := &http.Client{
+			Transport: &mockRoundTripper{
+				Response: &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(strings.NewReader("invalid json")),
+				},
+			},
+		}
+
+		ws := NewWeatherService("test-key", mockClient)
+		_, err := ws.FetchWeatherData("TestCity", 1)
+		if err == nil {
+			t.Fatal("Expected an error, got none")
+		}
+		if !strings.Contains(err.Error(), "failed to parse weather data") {
+			t.Errorf("Expected parse error, got %v", err)
+		}
+	})
+}
+
+// TestFormatCurrentWeather tests the formatting of current weather data
+func TestFormatCurrentWeather(t *testing.T) {
+	weather := &Weather{
+		Location: struct {
+			Name    string `json:"name"`
+			Country string `json:"country"`
+		}{Name: "Casablanca", Country: "Morocco"},
+		Current: struct {
+			TempC     float64 `json:"temp_c"`
+			Condition struct {
+				Text string `json:"text"`
+				Icon string `json:"icon"`
+			} `json:"condition"`
+		}{TempC: 22.0, Condition: struct {
+			Text string `json:"text"`
+			Icon string `json:"icon"`
+		}{Text: "Partly cloudy"}},
+	}
+	expected := "Casablanca, Morocco: 22.0°C, Partly cloudy"
+	result := FormatCurrentWeather(weather)
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+// TestWeatherHandler tests the main HTTP handler
+func TestWeatherHandler(t *testing.T) {
+	t.Run("Successful request with city", func(t *testing.T) {
+		// Mock the API response for this specific test case
+		mockClient := &http.Client{
+			Transport: &mockRoundTripper{
+				Response: &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(strings.NewReader(`{"location": {"name": "TestCity", "country": "TestCountry"}, "current": {"temp_c": 25.0, "condition": {"text": "Sunny"}}}`)),
+				},
+			},
+		}
+
+		// Create a new App instance and inject the mock weather service
+		app := &App{weatherService: NewWeatherService("test-key", mockClient)}
+		req := httptest.NewRequest("GET", "/weather?city=TestCity", nil)
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(app.weatherHandler)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+		expected := `{"weather":"TestCity, TestCountry: 25.0°C, Sunny"}` + "\n"
+		if rr.Body.String() != expected {
+			t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), expected)
+		}
+	})
+
+	t.Run("Request without city", func(t *testing.T) {
+		// Mock the API response for this specific test case
+		mockClient := &http.Client{
+			Transport: &mockRoundTripper{
+				Response: &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(strings.NewReader(`{"location": {"name": "Algiers", "country": "Algeria"}, "current": {"temp_c": 20.0, "condition": {"text": "Cloudy"}}}`)),
+				},
+			},
+		}
+
+		app := &App{weatherService: NewWeatherService("test-key", mockClient)}
+		req := httptest.NewRequest("GET", "/weather", nil)
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(app.weatherHandler)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+		expected := `{"weather":"Algiers, Algeria: 20.0°C, Cloudy"}` + "\n"
+		if rr.Body.String() != expected {
+			t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), expected)
+		}
+	})
+
+	t.Run("API error response", func(t *testing.T) {
+		// Mock the API response to be a 401 error
+		mockClient := &http.Client{
+			Transport: &mockRoundTripper{
+				Response: &http.Response{
+					StatusCode: 401,
+					Body:       io.NopCloser(strings.NewReader("Unauthorized")),
+				},
+			},
+		}
+
+		app := &App{weatherService: NewWeatherService("test-key", mockClient)}
+		req := httptest.NewRequest("GET", "/weather?city=TestCity", nil)
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(app.weatherHandler)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusInternalServerError {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusInternalServerError)
+		}
+		expected := "Error fetching weather: provider weatherapi: API returned status 401 for city TestCity\n"
+		if rr.Body.String() != expected {
+			t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), expected)
+		}
+	})
+
+	t.Run("API key not set", func(t *testing.T) {
+		// Mock the API response to be a 400 error because the key is missing
+		mockClient := &http.Client{
+			Transport: &mockRoundTripper{
+				Response: &http.Response{
+					StatusCode: 400,
+					Body:       io.NopCloser(strings.NewReader("Bad Request")),
+				},
+			},
+		}
+
+		app := &App{
+			weatherService: NewWeatherService("", mockClient),
+		}
+
+		req := httptest.NewRequest("GET", "/weather", nil)
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(app.weatherHandler)
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusInternalServerError {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusInternalServerError)
+		}
+
+		expected := "Error fetching weather: provider weatherapi: API returned status 400 for city Algiers\n"
+		if rr.Body.String() != expected {
+			t.Errorf("handler returned unexpected body: got %q want %q", rr.Body.String(), expected)
+		}
+	})
+}
+
+// TestFetchWeatherDataDaysValidation table-drives the days bounds FetchWeatherData enforces
+func TestFetchWeatherDataDaysValidation(t *testing.T) {
+	newMockClient := func() *http.Client {
+		return &http.Client{
+			Transport: &mockRoundTripper{
+				Response: &http.Response{
+					StatusCode: 200,
+					Body: io.NopCloser(strings.NewReader(`{
+						"location": {"name": "TestCity", "country": "TestCountry"},
+						"current": {"temp_c": 25.5, "condition": {"text": "Sunny"}},
+						"forecast": {"forecastday": [{"hour": []}]}
+					}`)),
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		days    int
+		wantErr bool
+	}{
+		{"below minimum", 0, true},
+		{"minimum", 1, false},
+		{"maximum", 10, false},
+		{"above maximum", 11, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := NewWeatherService("test-key", newMockClient())
+			_, err := ws.FetchWeatherData("TestCity", tt.days)
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected an error for days=%d, got none", tt.days)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("expected no error for days=%d, got %v", tt.days, err)
+			}
+		})
+	}
+}
+
+// TestFormatForecast tests the formatting of multi-day forecast data
+func TestFormatForecast(t *testing.T) {
+	t.Run("with hourly data", func(t *testing.T) {
+		weather := &Weather{}
+		weather.Location.Name = "Casablanca"
+		weather.Location.Country = "Morocco"
+		weather.Forecast.Forecastday = make([]struct {
+			Hour []HourForecast `json:"hour"`
+		}, 1)
+		weather.Forecast.Forecastday[0].Hour = make([]HourForecast, 2)
+		weather.Forecast.Forecastday[0].Hour[0].TimeEpoch = 1000
+		weather.Forecast.Forecastday[0].Hour[0].TempC = 20.0
+		weather.Forecast.Forecastday[0].Hour[0].Condition.Text = "Clear"
+		weather.Forecast.Forecastday[0].Hour[1].TimeEpoch = 1043200
+		weather.Forecast.Forecastday[0].Hour[1].TempC = 24.0
+		weather.Forecast.Forecastday[0].Hour[1].Condition.Text = "Sunny"
+
+		expected := "Casablanca, Morocco\nDay 1: 22.0°C avg, Clear"
+		result := FormatForecast(weather)
+		if result != expected {
+			t.Errorf("Expected %q, got %q", expected, result)
+		}
+	})
+
+	t.Run("without hourly data", func(t *testing.T) {
+		weather := &Weather{}
+		weather.Location.Name = "Casablanca"
+		weather.Location.Country = "Morocco"
+		weather.Forecast.Forecastday = make([]struct {
+			Hour []HourForecast `json:"hour"`
+		}, 1)
+
+		expected := "Casablanca, Morocco\nDay 1: no hourly data available"
+		result := FormatForecast(weather)
+		if result != expected {
+			t.Errorf("Expected %q, got %q", expected, result)
+		}
+	})
+}
+
+// TestFormatHourly tests the formatting of the hourly breakdown
+func TestFormatHourly(t *testing.T) {
+	t.Run("with hourly data", func(t *testing.T) {
+		weather := &Weather{}
+		weather.Location.Name = "Casablanca"
+		weather.Location.Country = "Morocco"
+		weather.Forecast.Forecastday = make([]struct {
+			Hour []HourForecast `json:"hour"`
+		}, 1)
+		weather.Forecast.Forecastday[0].Hour = make([]HourForecast, 1)
+		weather.Forecast.Forecastday[0].Hour[0].TimeEpoch = 1000
+		weather.Forecast.Forecastday[0].Hour[0].TempC = 21.5
+		weather.Forecast.Forecastday[0].Hour[0].Condition.Text = "Clear"
+		weather.Forecast.Forecastday[0].Hour[0].ChanceOfRain = 10
+
+		expected := "Casablanca, Morocco\n1000: 21.5°C, Clear, 10% rain"
+		result := FormatHourly(weather)
+		if result != expected {
+			t.Errorf("Expected %q, got %q", expected, result)
+		}
+	})
+
+	t.Run("without forecast data", func(t *testing.T) {
+		weather := &Weather{}
+		weather.Location.Name = "Casablanca"
+		weather.Location.Country = "Morocco"
+
+		expected := "Casablanca, Morocco: no hourly data available"
+		result := FormatHourly(weather)
+		if result != expected {
+			t.Errorf("Expected %q, got %q", expected, result)
+		}
+	})
+}
+
+// TestForecastHandler tests the /forecast endpoint
+func TestForecastHandler(t *testing.T) {
+	mockClient := &http.Client{
+		Transport: &mockRoundTripper{
+			Response: &http.Response{
+				StatusCode: 200,
+				Body: io.NopCloser(strings.NewReader(`{
+					"location": {"name": "TestCity", "country": "TestCountry"},
+					"current": {"temp_c": 25.0, "condition": {"text": "Sunny"}},
+					"forecast": {"forecastday": [{"hour": [{"time_epoch": 1000, "temp_c": 20.0, "condition": {"text": "Clear"}, "chance_of_rain": 5}]}]}
+				}`)),
+			},
+		},
+	}
+
+	app := &App{weatherService: NewWeatherService("test-key", mockClient)}
+	req := httptest.NewRequest("GET", "/forecast?city=TestCity&days=3", nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(app.forecastHandler)
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if !strings.Contains(rr.Body.String(), `"forecastday"`) {
+		t.Errorf("expected forecast body to include forecastday, got %q", rr.Body.String())
+	}
+
+	t.Run("invalid days", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/forecast?city=TestCity&days=abc", nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+		}
+	})
+}
+
+// TestHourlyHandler tests the /hourly endpoint
+func TestHourlyHandler(t *testing.T) {
+	mockClient := &http.Client{
+		Transport: &mockRoundTripper{
+			Response: &http.Response{
+				StatusCode: 200,
+				Body: io.NopCloser(strings.NewReader(`{
+					"location": {"name": "TestCity", "country": "TestCountry"},
+					"current": {"temp_c": 25.0, "condition": {"text": "Sunny"}},
+					"forecast": {"forecastday": [{"hour": [{"time_epoch": 1000, "temp_c": 20.0, "condition": {"text": "Clear"}, "chance_of_rain": 5}]}]}
+				}`)),
+			},
+		},
+	}
+
+	app := &App{weatherService: NewWeatherService("test-key", mockClient)}
+	req := httptest.NewRequest("GET", "/hourly?city=TestCity", nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(app.hourlyHandler)
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if !strings.Contains(rr.Body.String(), `"hourly"`) {
+		t.Errorf("expected hourly body to include hourly key, got %q", rr.Body.String())
+	}
+}
+
+// TestIndexHandler tests the HTML dashboard's root page
+func TestIndexHandler(t *testing.T) {
+	mockClient := &http.Client{
+		Transport: &mockRoundTripper{
+			Response: &http.Response{
+				StatusCode: 200,
+				Body: io.NopCloser(strings.NewReader(`{
+					"location": {"name": "TestCity", "country": "TestCountry"},
+					"current": {"temp_c": 25.0, "condition": {"text": "Sunny", "icon": "//cdn.example/sun.png"}},
+					"forecast": {"forecastday": [{"hour": []}]}
+				}`)),
+			},
+		},
+	}
+
+	app := &App{weatherService: NewWeatherService("test-key", mockClient)}
+	req := httptest.NewRequest("GET", "/?city=TestCity", nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(app.indexHandler)
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if !strings.Contains(rr.Body.String(), "TestCity, TestCountry") {
+		t.Errorf("expected page to render the city name, got %q", rr.Body.String())
+	}
+}
+
+// TestWeatherFragmentHandler tests the htmx partial returned by /weather/fragment
+func TestWeatherFragmentHandler(t *testing.T) {
+	mockClient := &http.Client{
+		Transport: &mockRoundTripper{
+			Response: &http.Response{
+				StatusCode: 200,
+				Body: io.NopCloser(strings.NewReader(`{
+					"location": {"name": "TestCity", "country": "TestCountry"},
+					"current": {"temp_c": 25.0, "condition": {"text": "Sunny", "icon": "//cdn.example/sun.png"}},
+					"forecast": {"forecastday": [{"hour": []}]}
+				}`)),
+			},
+		},
+	}
+
+	app := &App{weatherService: NewWeatherService("test-key", mockClient)}
+	form := strings.NewReader("city=TestCity")
+	req := httptest.NewRequest("POST", "/weather/fragment", form)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(app.weatherFragmentHandler)
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if !strings.Contains(rr.Body.String(), `id="weather-card"`) {
+		t.Errorf("expected fragment to contain the weather card, got %q", rr.Body.String())
+	}
+}
+
+// TestHealthzHandler tests the liveness probe
+func TestHealthzHandler(t *testing.T) {
+	app := &App{}
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(app.healthzHandler).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+}
+
+// TestReadyzHandler tests the readiness probe against the upstream provider
+func TestReadyzHandler(t *testing.T) {
+	t.Run("ready when upstream succeeds", func(t *testing.T) {
+		mockClient := &http.Client{
+			Transport: &mockRoundTripper{
+				Response: &http.Response{
+					StatusCode: 200,
+					Body: io.NopCloser(strings.NewReader(`{
+						"location": {"name": "Algiers", "country": "Algeria"},
+						"current": {"temp_c": 20.0, "condition": {"text": "Cloudy"}}
+					}`)),
+				},
+			},
+		}
+		app := &App{weatherService: NewWeatherService("test-key", mockClient), defaultCity: "Algiers"}
+		req := httptest.NewRequest("GET", "/readyz", nil)
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(app.readyzHandler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+		}
+	})
+
+	t.Run("not ready when upstream fails", func(t *testing.T) {
+		mockClient := &http.Client{
+			Transport: &mockRoundTripper{
+				Response: &http.Response{
+					StatusCode: 500,
+					Body:       io.NopCloser(strings.NewReader("boom")),
+				},
+			},
+		}
+		app := &App{weatherService: NewWeatherService("test-key", mockClient), defaultCity: "Algiers"}
+		req := httptest.NewRequest("GET", "/readyz", nil)
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(app.readyzHandler).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusServiceUnavailable {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusServiceUnavailable)
+		}
+	})
+}
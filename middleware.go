@@ -0,0 +1,246 @@
+package main
+
+import (
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// middleware wraps a handler with additional behavior
+type middleware func(http.Handler) http.Handler
+
+// chain applies middlewares to h in order, so the first middleware listed runs first
+func chain(h http.Handler, mws ...middleware) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// statusRecorder wraps http.ResponseWriter to capture the status code for logging
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// requestIDMiddleware assigns each request a short random id, echoed back on the
+// X-Request-Id response header so loggingMiddleware (and the client) can key off it.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", newRequestID())
+		next.ServeHTTP(w, r)
+	})
+}
+
+func newRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// loggingMiddleware logs each request's method, path, status, latency and remote address
+// using structured logging (log/slog).
+func loggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		slog.Info("request",
+			"request_id", w.Header().Get("X-Request-Id"),
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"latency", time.Since(start),
+			"remote_addr", clientIP(r),
+		)
+	})
+}
+
+// recoverMiddleware converts a panic in the handler chain into a 500 response instead of
+// crashing the server.
+func recoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if err := recover(); err != nil {
+				slog.Error("panic recovered", "error", fmt.Sprint(err), "path", r.URL.Path)
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// corsMiddleware allows cross-origin requests so the dashboard can be embedded or
+// queried from other origins.
+func corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// gzipResponseWriter wraps http.ResponseWriter to transparently gzip the body
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	writer *gzip.Writer
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	return w.writer.Write(b)
+}
+
+// gzipMiddleware compresses every response, JSON and HTML alike, when the client
+// advertises gzip support
+func gzipMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+
+		next.ServeHTTP(&gzipResponseWriter{ResponseWriter: w, writer: gz}, r)
+	})
+}
+
+// tokenBucket is a simple per-key token bucket used for rate limiting
+type tokenBucket struct {
+	mu         sync.Mutex
+	tokens     float64
+	capacity   float64
+	refillRate float64 // tokens per second
+	lastRefill time.Time
+}
+
+func newTokenBucket(capacity, refillRate float64) *tokenBucket {
+	return &tokenBucket{tokens: capacity, capacity: capacity, refillRate: refillRate, lastRefill: time.Now()}
+}
+
+// Allow consumes one token if available, refilling based on elapsed time first
+func (b *tokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	b.tokens = min(b.capacity, b.tokens+elapsed*b.refillRate)
+	b.lastRefill = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// idleFor reports how long it has been since the bucket last served a request
+func (b *tokenBucket) idleFor(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return now.Sub(b.lastRefill)
+}
+
+// rateLimitIdleTTL bounds how long a client's bucket is kept around without activity.
+// Without this, a flood of distinct client IPs would grow rateLimiter.buckets forever.
+const rateLimitIdleTTL = 10 * time.Minute
+
+// rateLimitSweepInterval controls how often idle buckets are swept out
+const rateLimitSweepInterval = time.Minute
+
+// rateLimiter hands out a token bucket per client IP, keyed on first sight. A background
+// sweeper evicts buckets that have gone idle for longer than rateLimitIdleTTL.
+type rateLimiter struct {
+	mu         sync.Mutex
+	buckets    map[string]*tokenBucket
+	capacity   float64
+	refillRate float64
+}
+
+func newRateLimiter(capacity, refillRate float64) *rateLimiter {
+	rl := &rateLimiter{buckets: make(map[string]*tokenBucket), capacity: capacity, refillRate: refillRate}
+	go rl.sweepLoop()
+	return rl
+}
+
+func (rl *rateLimiter) allow(key string) bool {
+	rl.mu.Lock()
+	bucket, ok := rl.buckets[key]
+	if !ok {
+		bucket = newTokenBucket(rl.capacity, rl.refillRate)
+		rl.buckets[key] = bucket
+	}
+	rl.mu.Unlock()
+
+	return bucket.Allow()
+}
+
+// sweepLoop periodically evicts idle buckets until the process exits
+func (rl *rateLimiter) sweepLoop() {
+	ticker := time.NewTicker(rateLimitSweepInterval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		rl.sweep(now)
+	}
+}
+
+// sweep removes every bucket that has been idle for longer than rateLimitIdleTTL
+func (rl *rateLimiter) sweep(now time.Time) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	for key, bucket := range rl.buckets {
+		if bucket.idleFor(now) > rateLimitIdleTTL {
+			delete(rl.buckets, key)
+		}
+	}
+}
+
+// rateLimitMiddleware rejects requests once a client IP exceeds its token bucket
+func rateLimitMiddleware(rl *rateLimiter) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !rl.allow(clientIP(r)) {
+				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP extracts the request's remote IP, stripping the port
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
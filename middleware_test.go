@@ -0,0 +1,131 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestRecoverMiddleware tests that a panicking handler is converted into a 500 response
+func TestRecoverMiddleware(t *testing.T) {
+	panicking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest("GET", "/weather", nil)
+	rr := httptest.NewRecorder()
+	recoverMiddleware(panicking).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusInternalServerError {
+		t.Errorf("expected status %v, got %v", http.StatusInternalServerError, status)
+	}
+}
+
+// TestRequestIDMiddleware tests that a response gets a non-empty X-Request-Id header
+func TestRequestIDMiddleware(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	req := httptest.NewRequest("GET", "/weather", nil)
+	rr := httptest.NewRecorder()
+	requestIDMiddleware(ok).ServeHTTP(rr, req)
+
+	if rr.Header().Get("X-Request-Id") == "" {
+		t.Error("expected X-Request-Id header to be set")
+	}
+}
+
+// TestCorsMiddleware tests CORS headers and preflight handling
+func TestCorsMiddleware(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	t.Run("sets CORS headers", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/weather", nil)
+		rr := httptest.NewRecorder()
+		corsMiddleware(ok).ServeHTTP(rr, req)
+
+		if rr.Header().Get("Access-Control-Allow-Origin") != "*" {
+			t.Errorf("expected Access-Control-Allow-Origin header to be set")
+		}
+	})
+
+	t.Run("short-circuits preflight", func(t *testing.T) {
+		req := httptest.NewRequest("OPTIONS", "/weather", nil)
+		rr := httptest.NewRecorder()
+		corsMiddleware(ok).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusNoContent {
+			t.Errorf("expected status %v, got %v", http.StatusNoContent, status)
+		}
+	})
+}
+
+// TestGzipMiddleware tests that responses are gzip-compressed when requested
+func TestGzipMiddleware(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"weather":"Sunny"}`))
+	})
+
+	req := httptest.NewRequest("GET", "/weather", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	gzipMiddleware(ok).ServeHTTP(rr, req)
+
+	if rr.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected Content-Encoding: gzip, got %q", rr.Header().Get("Content-Encoding"))
+	}
+
+	reader, err := gzip.NewReader(rr.Body)
+	if err != nil {
+		t.Fatalf("expected a valid gzip body, got error: %v", err)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read gzip body: %v", err)
+	}
+	if string(body) != `{"weather":"Sunny"}` {
+		t.Errorf("expected decompressed body %q, got %q", `{"weather":"Sunny"}`, string(body))
+	}
+}
+
+// TestRateLimitMiddleware tests that requests beyond the bucket's capacity are rejected
+func TestRateLimitMiddleware(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	rl := newRateLimiter(1, 0)
+	handler := rateLimitMiddleware(rl)(ok)
+
+	req := httptest.NewRequest("GET", "/weather", nil)
+	req.RemoteAddr = "1.2.3.4:5555"
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, req)
+	if first.Code != http.StatusOK {
+		t.Fatalf("expected first request to succeed, got status %v", first.Code)
+	}
+
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, req)
+	if second.Code != http.StatusTooManyRequests {
+		t.Errorf("expected second request to be rate limited, got status %v", second.Code)
+	}
+}
+
+// TestRateLimiterSweep tests that idle buckets are evicted, bounding memory growth from
+// distinct client IPs
+func TestRateLimiterSweep(t *testing.T) {
+	rl := newRateLimiter(1, 0)
+	rl.allow("1.2.3.4")
+	rl.allow("5.6.7.8")
+
+	if len(rl.buckets) != 2 {
+		t.Fatalf("expected 2 buckets before sweeping, got %d", len(rl.buckets))
+	}
+
+	rl.sweep(time.Now().Add(rateLimitIdleTTL + time.Second))
+
+	if len(rl.buckets) != 0 {
+		t.Errorf("expected idle buckets to be evicted, got %d remaining", len(rl.buckets))
+	}
+}
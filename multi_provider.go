@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker tracks consecutive failures for a single provider and trips open
+// after a configurable threshold, resetting to half-open after a cooldown.
+type circuitBreaker struct {
+	mu               sync.Mutex
+	state            breakerState
+	consecutiveFails int
+	openedAt         time.Time
+	failureThreshold int
+	cooldown         time.Duration
+}
+
+func newCircuitBreaker(failureThreshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{failureThreshold: failureThreshold, cooldown: cooldown}
+}
+
+// Allow reports whether a call should be attempted, transitioning an open breaker to
+// half-open once the cooldown has elapsed.
+func (b *circuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) >= b.cooldown {
+			b.state = breakerHalfOpen
+			return true
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// RecordSuccess closes the breaker and resets its failure count
+func (b *circuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = breakerClosed
+	b.consecutiveFails = 0
+}
+
+// RecordFailure increments the failure count, opening the breaker once the threshold is hit
+func (b *circuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+		return
+	}
+
+	b.consecutiveFails++
+	if b.consecutiveFails >= b.failureThreshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// MultiProvider tries a chain of providers in order, skipping any whose circuit
+// breaker is currently open due to repeated failures.
+type MultiProvider struct {
+	providers []Provider
+	breakers  []*circuitBreaker
+}
+
+// NewMultiProvider builds a MultiProvider that tries providers in order. The breaker opens
+// after failureThreshold consecutive failures and half-opens again after cooldown.
+func NewMultiProvider(providers []Provider, failureThreshold int, cooldown time.Duration) *MultiProvider {
+	breakers := make([]*circuitBreaker, len(providers))
+	for i := range providers {
+		breakers[i] = newCircuitBreaker(failureThreshold, cooldown)
+	}
+	return &MultiProvider{providers: providers, breakers: breakers}
+}
+
+func (m *MultiProvider) Name() string { return "multi" }
+
+// FetchWeather tries each provider in order, skipping ones whose breaker is open, and
+// returns the first successful result. If every provider fails, it returns the last error.
+func (m *MultiProvider) FetchWeather(ctx context.Context, query string, days int) (*Weather, error) {
+	if len(m.providers) == 0 {
+		return nil, fmt.Errorf("no weather providers configured")
+	}
+
+	var lastErr error
+	for i, provider := range m.providers {
+		breaker := m.breakers[i]
+		if !breaker.Allow() {
+			lastErr = fmt.Errorf("provider %s: circuit breaker open", provider.Name())
+			continue
+		}
+
+		weather, err := provider.FetchWeather(ctx, query, days)
+		if err != nil {
+			breaker.RecordFailure()
+			lastErr = fmt.Errorf("provider %s: %w", provider.Name(), err)
+			continue
+		}
+
+		breaker.RecordSuccess()
+		return weather, nil
+	}
+
+	return nil, lastErr
+}
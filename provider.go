@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Provider is implemented by every weather backend WeatherService can fall back between.
+// Each implementation is responsible for normalizing its upstream response into Weather.
+type Provider interface {
+	Name() string
+	FetchWeather(ctx context.Context, query string, days int) (*Weather, error)
+}
+
+// WeatherAPIProvider fetches weather data from weatherapi.com
+type WeatherAPIProvider struct {
+	APIKey string
+	Client *http.Client
+}
+
+// NewWeatherAPIProvider creates a Provider backed by weatherapi.com
+func NewWeatherAPIProvider(apiKey string, client *http.Client) *WeatherAPIProvider {
+	return &WeatherAPIProvider{APIKey: apiKey, Client: client}
+}
+
+func (p *WeatherAPIProvider) Name() string { return "weatherapi" }
+
+// FetchWeather fetches weather data from the weatherapi.com forecast endpoint for the
+// given number of forecast days
+func (p *WeatherAPIProvider) FetchWeather(ctx context.Context, query string, days int) (*Weather, error) {
+	apiUrl := fmt.Sprintf("https://api.weatherapi.com/v1/forecast.json?key=%s&q=%s&days=%d", p.APIKey, query, days)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiUrl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d for city %s", resp.StatusCode, query)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var weather Weather
+	if err := json.Unmarshal(body, &weather); err != nil {
+		return nil, fmt.Errorf("failed to parse weather data: %w", err)
+	}
+
+	return &weather, nil
+}
+
+// buildProvidersFromEnv builds the provider chain used by MultiProvider, based on the
+// WEATHER_PROVIDERS env var (a comma-separated list, e.g. "weatherapi,openweathermap,openmeteo").
+// Unknown or misconfigured providers (missing API key) are skipped. If WEATHER_PROVIDERS is
+// unset, it defaults to just "weatherapi" to preserve existing behavior.
+func buildProvidersFromEnv(apiKey string, client *http.Client) []Provider {
+	order := os.Getenv("WEATHER_PROVIDERS")
+	if order == "" {
+		order = "weatherapi"
+	}
+
+	var providers []Provider
+	for _, name := range strings.Split(order, ",") {
+		switch strings.TrimSpace(name) {
+		case "weatherapi":
+			if apiKey != "" {
+				providers = append(providers, NewWeatherAPIProvider(apiKey, client))
+			}
+		case "openweathermap":
+			if key := os.Getenv("OPENWEATHERMAP_API_KEY"); key != "" {
+				providers = append(providers, NewOpenWeatherMapProvider(key, client))
+			}
+		case "openmeteo":
+			providers = append(providers, NewOpenMeteoProvider(client))
+		}
+	}
+	return providers
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// OpenMeteoProvider fetches weather data from Open-Meteo, which requires no API key
+type OpenMeteoProvider struct {
+	Client *http.Client
+}
+
+// NewOpenMeteoProvider creates a Provider backed by Open-Meteo
+func NewOpenMeteoProvider(client *http.Client) *OpenMeteoProvider {
+	return &OpenMeteoProvider{Client: client}
+}
+
+func (p *OpenMeteoProvider) Name() string { return "openmeteo" }
+
+type openMeteoGeocodeResponse struct {
+	Results []struct {
+		Name      string  `json:"name"`
+		Country   string  `json:"country"`
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	} `json:"results"`
+}
+
+type openMeteoForecastResponse struct {
+	Current struct {
+		Temperature2m float64 `json:"temperature_2m"`
+		WeatherCode   int     `json:"weather_code"`
+	} `json:"current"`
+}
+
+// FetchWeather geocodes query to coordinates, then fetches current conditions for them.
+// Open-Meteo's current-conditions call has no forecast days, so days is ignored.
+func (p *OpenMeteoProvider) FetchWeather(ctx context.Context, query string, days int) (*Weather, error) {
+	geocode, err := p.geocode(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(geocode.Results) == 0 {
+		return nil, fmt.Errorf("no location found for city %s", query)
+	}
+	location := geocode.Results[0]
+
+	forecastURL := fmt.Sprintf(
+		"https://api.open-meteo.com/v1/forecast?latitude=%f&longitude=%f&current=temperature_2m,weather_code",
+		location.Latitude, location.Longitude,
+	)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, forecastURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d for city %s", resp.StatusCode, query)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var forecast openMeteoForecastResponse
+	if err := json.Unmarshal(body, &forecast); err != nil {
+		return nil, fmt.Errorf("failed to parse weather data: %w", err)
+	}
+
+	return adaptOpenMeteo(location.Name, location.Country, forecast), nil
+}
+
+// geocode resolves a city name into Open-Meteo's geocoding results
+func (p *OpenMeteoProvider) geocode(ctx context.Context, query string) (*openMeteoGeocodeResponse, error) {
+	geocodeURL := fmt.Sprintf("https://geocoding-api.open-meteo.com/v1/search?name=%s&count=1", url.QueryEscape(query))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, geocodeURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to geocode city: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoding API returned status %d for city %s", resp.StatusCode, query)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read geocoding response: %w", err)
+	}
+
+	var geocode openMeteoGeocodeResponse
+	if err := json.Unmarshal(body, &geocode); err != nil {
+		return nil, fmt.Errorf("failed to parse geocoding response: %w", err)
+	}
+
+	return &geocode, nil
+}
+
+// openMeteoWeatherCodes maps a subset of WMO weather codes to human-readable text
+var openMeteoWeatherCodes = map[int]string{
+	0:  "Clear sky",
+	1:  "Mainly clear",
+	2:  "Partly cloudy",
+	3:  "Overcast",
+	45: "Fog",
+	61: "Slight rain",
+	63: "Moderate rain",
+	65: "Heavy rain",
+	71: "Slight snow",
+	95: "Thunderstorm",
+}
+
+// adaptOpenMeteo normalizes an Open-Meteo response into the shared Weather struct
+func adaptOpenMeteo(name, country string, forecast openMeteoForecastResponse) *Weather {
+	var weather Weather
+	weather.Location.Name = name
+	weather.Location.Country = country
+	weather.Current.TempC = forecast.Current.Temperature2m
+	if text, ok := openMeteoWeatherCodes[forecast.Current.WeatherCode]; ok {
+		weather.Current.Condition.Text = text
+	} else {
+		weather.Current.Condition.Text = "Unknown"
+	}
+	return &weather
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// OpenWeatherMapProvider fetches weather data from OpenWeatherMap
+type OpenWeatherMapProvider struct {
+	APIKey string
+	Client *http.Client
+}
+
+// NewOpenWeatherMapProvider creates a Provider backed by OpenWeatherMap
+func NewOpenWeatherMapProvider(apiKey string, client *http.Client) *OpenWeatherMapProvider {
+	return &OpenWeatherMapProvider{APIKey: apiKey, Client: client}
+}
+
+func (p *OpenWeatherMapProvider) Name() string { return "openweathermap" }
+
+// owmResponse mirrors the subset of OpenWeatherMap's current-weather JSON we care about
+type owmResponse struct {
+	Name string `json:"name"`
+	Sys  struct {
+		Country string `json:"country"`
+	} `json:"sys"`
+	Main struct {
+		Temp float64 `json:"temp"`
+	} `json:"main"`
+	Weather []struct {
+		Description string `json:"description"`
+		Icon        string `json:"icon"`
+	} `json:"weather"`
+}
+
+// FetchWeather fetches current weather from OpenWeatherMap and adapts it into a Weather.
+// OpenWeatherMap's free current-weather endpoint has no forecast days, so days is ignored.
+func (p *OpenWeatherMapProvider) FetchWeather(ctx context.Context, query string, days int) (*Weather, error) {
+	apiUrl := fmt.Sprintf("https://api.openweathermap.org/data/2.5/weather?q=%s&appid=%s&units=metric", query, p.APIKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiUrl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d for city %s", resp.StatusCode, query)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var owm owmResponse
+	if err := json.Unmarshal(body, &owm); err != nil {
+		return nil, fmt.Errorf("failed to parse weather data: %w", err)
+	}
+
+	return adaptOWM(owm), nil
+}
+
+// adaptOWM normalizes an OpenWeatherMap response into the shared Weather struct
+func adaptOWM(owm owmResponse) *Weather {
+	var weather Weather
+	weather.Location.Name = owm.Name
+	weather.Location.Country = owm.Sys.Country
+	weather.Current.TempC = owm.Main.Temp
+	if len(owm.Weather) > 0 {
+		weather.Current.Condition.Text = owm.Weather[0].Description
+		weather.Current.Condition.Icon = owm.Weather[0].Icon
+	}
+	return &weather
+}
@@ -0,0 +1,27 @@
+package main
+
+import "net/http"
+
+// newRouter builds the application's route table and wraps it in the shared middleware
+// chain. It uses Go 1.22's method+pattern ServeMux matching instead of a third-party router.
+func newRouter(app *App, rl *rateLimiter) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /weather", app.weatherHandler)
+	mux.HandleFunc("GET /forecast", app.forecastHandler)
+	mux.HandleFunc("GET /hourly", app.hourlyHandler)
+	mux.HandleFunc("GET /metrics", app.metricsHandler)
+	mux.HandleFunc("GET /healthz", app.healthzHandler)
+	mux.HandleFunc("GET /readyz", app.readyzHandler)
+	mux.HandleFunc("GET /{$}", app.indexHandler)
+	mux.HandleFunc("POST /weather/fragment", app.weatherFragmentHandler)
+
+	return chain(mux,
+		recoverMiddleware,
+		requestIDMiddleware,
+		loggingMiddleware,
+		corsMiddleware,
+		rateLimitMiddleware(rl),
+		gzipMiddleware,
+	)
+}
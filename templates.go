@@ -0,0 +1,30 @@
+package main
+
+import (
+	"embed"
+	"html/template"
+	"net/http"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// templates holds every parsed page and partial, keyed by the name given to {{define}}
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+
+// weatherCardView is the data passed to the "weather-card" partial and the "index" page
+type weatherCardView struct {
+	City  string
+	Error string
+
+	Weather *Weather
+	Hours   []HourForecast
+}
+
+// renderTemplate executes the named template and writes any execution error to w
+func renderTemplate(w http.ResponseWriter, name string, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := templates.ExecuteTemplate(w, name, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}